@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Camada de aplicação (autenticação/autorização)
+
+var (
+	// ErrInvalidCredentials é devolvido por Login quando o e-mail não existe
+	// ou a senha informada não confere com o hash armazenado.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrMissingToken é devolvido pelo middleware RequireAuth quando o header
+	// Authorization não está presente ou não segue o esquema Bearer.
+	ErrMissingToken = errors.New("missing bearer token")
+	// ErrInvalidToken é devolvido quando o JWT não pode ser validado ou foi
+	// revogado por logout.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// TokenStore é a porta usada por AuthService para revogar JWTs no logout.
+type TokenStore interface {
+	Revoke(ctx context.Context, token string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, token string) (bool, error)
+}
+
+// InMemoryTokenStore é um TokenStore adequado para testes e desenvolvimento
+// local: os tokens revogados vivem apenas na memória do processo.
+type InMemoryTokenStore struct {
+	revoked map[string]time.Time
+}
+
+func NewInMemoryTokenStore() *InMemoryTokenStore {
+	return &InMemoryTokenStore{revoked: make(map[string]time.Time)}
+}
+
+func (s *InMemoryTokenStore) Revoke(_ context.Context, token string, expiresAt time.Time) error {
+	s.revoked[token] = expiresAt
+	return nil
+}
+
+func (s *InMemoryTokenStore) IsRevoked(_ context.Context, token string) (bool, error) {
+	expiresAt, ok := s.revoked[token]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(expiresAt) {
+		delete(s.revoked, token)
+		return false, nil
+	}
+	return true, nil
+}
+
+// SQLiteTokenStore persiste tokens revogados em uma tabela `revoked_tokens`,
+// sobrevivendo a reinícios do processo.
+type SQLiteTokenStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteTokenStore(db *sql.DB) (*SQLiteTokenStore, error) {
+	if err := Migrate(db); err != nil {
+		return nil, err
+	}
+	return &SQLiteTokenStore{db: db}, nil
+}
+
+func (s *SQLiteTokenStore) Revoke(_ context.Context, token string, expiresAt time.Time) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO revoked_tokens (token, expires_at) VALUES (?, ?)", token, expiresAt)
+	return err
+}
+
+func (s *SQLiteTokenStore) IsRevoked(_ context.Context, token string) (bool, error) {
+	row := s.db.QueryRow("SELECT expires_at FROM revoked_tokens WHERE token = ?", token)
+	var expiresAt time.Time
+	err := row.Scan(&expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if time.Now().After(expiresAt) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// AuthConfig reúne os parâmetros necessários para assinar e validar JWTs.
+type AuthConfig struct {
+	JWTSecret string
+	TokenTTL  time.Duration
+}
+
+// AuthService cuida de registro, login e logout, mantendo a autenticação
+// separada das operações de CRUD já expostas por UserService.
+type AuthService struct {
+	userRepository UserRepository
+	tokenStore     TokenStore
+	config         AuthConfig
+}
+
+func NewAuthService(userRepository UserRepository, tokenStore TokenStore, config AuthConfig) *AuthService {
+	return &AuthService{
+		userRepository: userRepository,
+		tokenStore:     tokenStore,
+		config:         config,
+	}
+}
+
+// authClaims são as claims customizadas embutidas no JWT emitido por Login.
+type authClaims struct {
+	UserID int `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+func (s *AuthService) Register(ctx context.Context, name, email, password string) (*User, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		Name:     name,
+		Email:    email,
+		Password: string(hashed),
+	}
+	if err := s.userRepository.SaveUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+	user, err := s.userRepository.GetUserByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return "", ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	claims := authClaims{
+		UserID: user.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.TokenTTL)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.config.JWTSecret))
+}
+
+func (s *AuthService) LogOut(ctx context.Context, token string) error {
+	claims, err := s.parseToken(token)
+	if err != nil {
+		return err
+	}
+	return s.tokenStore.Revoke(ctx, token, claims.ExpiresAt.Time)
+}
+
+// Authenticate valida o JWT (assinatura, expiração e revogação) e devolve o
+// usuário autenticado correspondente às claims.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*User, error) {
+	claims, err := s.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.tokenStore.IsRevoked(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidToken
+	}
+
+	return s.userRepository.GetUser(ctx, claims.UserID)
+}
+
+func (s *AuthService) parseToken(tokenString string) (*authClaims, error) {
+	claims := &authClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return []byte(s.config.JWTSecret), nil
+	})
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// authenticatedUserKey é a chave usada para guardar o *User autenticado no
+// contexto da requisição Gin.
+type authenticatedUserKey struct{}
+
+// RequireAuth é um middleware Gin que extrai o Bearer token do header
+// Authorization, valida o JWT via AuthService e injeta o *User autenticado
+// no contexto da requisição.
+func RequireAuth(authService *AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ErrMissingToken.Error()})
+			return
+		}
+
+		user, err := authService.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set("user", user)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), authenticatedUserKey{}, user))
+		c.Next()
+	}
+}
+
+// AuthenticatedUser extrai o *User injetado por RequireAuth do contexto da
+// requisição.
+func AuthenticatedUser(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(authenticatedUserKey{}).(*User)
+	return user, ok
+}
+
+// bearerToken extrai o token do header Authorization no esquema Bearer.
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(header, prefix), true
+}
+
+// Camada de interface (adapter de entrada HTTP de autenticação)
+
+// registerRequest é o corpo esperado por POST /register.
+type registerRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// loginRequest é o corpo esperado por POST /login.
+type loginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// tokenResponse é o corpo devolvido por POST /login com o JWT emitido.
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+// ConfigureAuthRoutes liga o AuthService existente a um *gin.Engine,
+// expondo registro, login e logout. /logout exige um Bearer token válido,
+// já que é ele quem é revogado.
+func ConfigureAuthRoutes(router *gin.Engine, authService *AuthService) {
+	router.POST("/register", registerHandler(authService))
+	router.POST("/login", loginHandler(authService))
+	router.POST("/logout", RequireAuth(authService), logoutHandler(authService))
+}
+
+func registerHandler(authService *AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req registerRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		user, err := authService.Register(c.Request.Context(), req.Name, req.Email, req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, newUserResponse(user))
+	}
+}
+
+func loginHandler(authService *AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req loginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		token, err := authService.Login(c.Request.Context(), req.Email, req.Password)
+		if errors.Is(err, ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, tokenResponse{Token: token})
+	}
+}
+
+func logoutHandler(authService *AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ErrMissingToken.Error()})
+			return
+		}
+
+		if err := authService.LogOut(c.Request.Context(), token); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Camada de domínio (eventos de ciclo de vida do usuário)
+
+// DomainEvent é implementado por todo evento de domínio publicado após uma
+// mutação bem-sucedida em UserService.
+type DomainEvent interface {
+	EventName() string
+	OccurredAt() time.Time
+}
+
+type UserCreated struct {
+	User *User
+	At   time.Time
+}
+
+func (e UserCreated) EventName() string     { return "user.created" }
+func (e UserCreated) OccurredAt() time.Time { return e.At }
+
+type UserUpdated struct {
+	User *User
+	At   time.Time
+}
+
+func (e UserUpdated) EventName() string     { return "user.updated" }
+func (e UserUpdated) OccurredAt() time.Time { return e.At }
+
+type UserDeleted struct {
+	UserID int
+	At     time.Time
+}
+
+func (e UserDeleted) EventName() string     { return "user.deleted" }
+func (e UserDeleted) OccurredAt() time.Time { return e.At }
+
+// EventPublisher é a porta usada por UserService para notificar adapters
+// externos (e-mail, auditoria, webhooks) sobre mudanças no ciclo de vida do
+// usuário, sem acoplar UserService a esses adapters.
+type EventPublisher interface {
+	Publish(ctx context.Context, event DomainEvent) error
+}
+
+// InProcessEventPublisher distribui eventos, via canal, para subscribers
+// registrados no mesmo processo. Entrega é best-effort: se o canal de um
+// subscriber estiver cheio, o evento é descartado para ele.
+type InProcessEventPublisher struct {
+	mu          sync.RWMutex
+	subscribers map[int]chan DomainEvent
+	nextID      int
+}
+
+func NewInProcessEventPublisher() *InProcessEventPublisher {
+	return &InProcessEventPublisher{subscribers: make(map[int]chan DomainEvent)}
+}
+
+func (p *InProcessEventPublisher) Publish(_ context.Context, event DomainEvent) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, ch := range p.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe roda handler em sua própria goroutine a cada evento publicado,
+// devolvendo uma função para cancelar a inscrição.
+func (p *InProcessEventPublisher) Subscribe(handler func(DomainEvent)) (unsubscribe func()) {
+	p.mu.Lock()
+	id := p.nextID
+	p.nextID++
+	ch := make(chan DomainEvent, 16)
+	p.subscribers[id] = ch
+	p.mu.Unlock()
+
+	go func() {
+		for event := range ch {
+			handler(event)
+		}
+	}()
+
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if ch, ok := p.subscribers[id]; ok {
+			delete(p.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// OutboxEventPublisher persiste eventos na tabela `outbox`. Quando gravado
+// via EnqueueInTx na mesma transação de uma mutação de usuário, garante
+// entrega at-least-once mesmo que o processo morra entre o commit e o
+// dispatch do evento.
+type OutboxEventPublisher struct {
+	db *sql.DB
+}
+
+func NewOutboxEventPublisher(db *sql.DB) *OutboxEventPublisher {
+	return &OutboxEventPublisher{db: db}
+}
+
+// Publish grava o evento em sua própria transação. Para atomicidade com
+// outra escrita, use EnqueueInTx dentro da transação dessa escrita.
+func (p *OutboxEventPublisher) Publish(ctx context.Context, event DomainEvent) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := p.EnqueueInTx(ctx, tx, event); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// EnqueueInTx grava event na tabela outbox usando a transação tx já aberta
+// pelo chamador.
+func (p *OutboxEventPublisher) EnqueueInTx(ctx context.Context, tx *sql.Tx, event DomainEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO outbox (event_name, payload, occurred_at, dispatched) VALUES (?, ?, ?, 0)",
+		event.EventName(), payload, event.OccurredAt())
+	return err
+}
+
+type outboxRow struct {
+	id        int64
+	eventName string
+	payload   []byte
+}
+
+// Dispatch faz uma rodada de polling sobre eventos pendentes, encaminhando
+// cada um para downstream e marcando-o como despachado só após sucesso.
+func (p *OutboxEventPublisher) Dispatch(ctx context.Context, downstream func(eventName string, payload []byte) error) error {
+	rows, err := p.db.QueryContext(ctx, "SELECT id, event_name, payload FROM outbox WHERE dispatched = 0 ORDER BY id")
+	if err != nil {
+		return err
+	}
+	var pending []outboxRow
+	for rows.Next() {
+		var row outboxRow
+		if err := rows.Scan(&row.id, &row.eventName, &row.payload); err != nil {
+			rows.Close()
+			return err
+		}
+		pending = append(pending, row)
+	}
+	rows.Close()
+
+	for _, row := range pending {
+		if err := downstream(row.eventName, row.payload); err != nil {
+			return fmt.Errorf("outbox: dispatching event %d (%s): %w", row.id, row.eventName, err)
+		}
+		if _, err := p.db.ExecContext(ctx, "UPDATE outbox SET dispatched = 1 WHERE id = ?", row.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDispatcher chama Dispatch periodicamente em sua própria goroutine até
+// que ctx seja cancelado ou a função stop devolvida seja chamada.
+func (p *OutboxEventPublisher) RunDispatcher(ctx context.Context, interval time.Duration, downstream func(eventName string, payload []byte) error) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = p.Dispatch(ctx, downstream)
+			}
+		}
+	}()
+	return cancel
+}
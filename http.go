@@ -0,0 +1,166 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Camada de interface (adapter de entrada HTTP)
+
+// createUserRequest é o corpo esperado por POST /users.
+type createUserRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+}
+
+// updateUserRequest é o corpo esperado por PUT /users/:id.
+type updateUserRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+}
+
+// userResponse é a representação de um User devolvida pela API.
+type userResponse struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func newUserResponse(user *User) userResponse {
+	return userResponse{
+		ID:    user.ID,
+		Name:  user.Name,
+		Email: user.Email,
+	}
+}
+
+// ConfigureUserRoutes liga o UserService existente a um *gin.Engine, expondo
+// as operações de CRUD de usuário como uma API REST em JSON. Todas as rotas
+// exigem um Bearer token válido, verificado por RequireAuth.
+func ConfigureUserRoutes(router *gin.Engine, svc *UserService, authService *AuthService) {
+	users := router.Group("/users", RequireAuth(authService))
+	users.GET("", listUsersHandler(svc))
+	users.GET("/:id", getUserHandler(svc))
+	users.POST("", createUserHandler(svc))
+	users.PUT("/:id", updateUserHandler(svc))
+	users.DELETE("/:id", deleteUserHandler(svc))
+}
+
+// listUsersResponse é o corpo devolvido por GET /users, incluindo o total de
+// registros que casam com os filtros para permitir paginação no cliente.
+type listUsersResponse struct {
+	Items []userResponse `json:"items"`
+	Total int64          `json:"total"`
+}
+
+func listUsersHandler(svc *UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, _ := strconv.Atoi(c.Query("page"))
+		pageSize, _ := strconv.Atoi(c.Query("page_size"))
+
+		result, err := svc.ListUsers(c.Request.Context(), ListUsersQuery{
+			Page:      page,
+			PageSize:  pageSize,
+			NameLike:  c.Query("name"),
+			EmailLike: c.Query("email"),
+			SortBy:    c.Query("sort_by"),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		items := make([]userResponse, 0, len(result.Items))
+		for _, user := range result.Items {
+			items = append(items, newUserResponse(user))
+		}
+		c.JSON(http.StatusOK, listUsersResponse{Items: items, Total: result.Total})
+	}
+}
+
+func getUserHandler(svc *UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		user, err := svc.GetUser(c.Request.Context(), userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, newUserResponse(user))
+	}
+}
+
+func createUserHandler(svc *UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req createUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		user, err := svc.CreateUser(c.Request.Context(), req.Name, req.Email)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Header("Location", fmt.Sprintf("/users/%d", user.ID))
+		c.JSON(http.StatusCreated, newUserResponse(user))
+	}
+}
+
+func updateUserHandler(svc *UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		var req updateUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := svc.UpdateUser(c.Request.Context(), userID, req.Name, req.Email); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+}
+
+func deleteUserHandler(svc *UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		if err := svc.DeleteUser(c.Request.Context(), userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
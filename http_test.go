@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockUserRepository é um UserRepository de teste: cada método delega para o
+// campo Func correspondente, deixando cada caso de teste configurar só o
+// comportamento que precisa.
+type mockUserRepository struct {
+	GetUserFunc        func(ctx context.Context, userID int) (*User, error)
+	GetUserByEmailFunc func(ctx context.Context, email string) (*User, error)
+	GetAllUsersFunc    func(ctx context.Context) ([]*User, error)
+	ListUsersFunc      func(ctx context.Context, query ListUsersQuery) (ListUsersResult, error)
+	SaveUserFunc       func(ctx context.Context, user *User) error
+	UpdateUserFunc     func(ctx context.Context, user *User) error
+	DeleteUserFunc     func(ctx context.Context, userID int) error
+}
+
+func (m *mockUserRepository) GetUser(ctx context.Context, userID int) (*User, error) {
+	return m.GetUserFunc(ctx, userID)
+}
+
+func (m *mockUserRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	return m.GetUserByEmailFunc(ctx, email)
+}
+
+func (m *mockUserRepository) GetAllUsers(ctx context.Context) ([]*User, error) {
+	return m.GetAllUsersFunc(ctx)
+}
+
+func (m *mockUserRepository) ListUsers(ctx context.Context, query ListUsersQuery) (ListUsersResult, error) {
+	return m.ListUsersFunc(ctx, query)
+}
+
+func (m *mockUserRepository) SaveUser(ctx context.Context, user *User) error {
+	return m.SaveUserFunc(ctx, user)
+}
+
+func (m *mockUserRepository) UpdateUser(ctx context.Context, user *User) error {
+	return m.UpdateUserFunc(ctx, user)
+}
+
+func (m *mockUserRepository) DeleteUser(ctx context.Context, userID int) error {
+	return m.DeleteUserFunc(ctx, userID)
+}
+
+const testJWTSecret = "test-secret"
+
+// newTestRouter monta o UserService de testes a partir de repo e o protege
+// com RequireAuth, assim como ConfigureUserRoutes faz em produção. A
+// autenticação em si usa um repositório próprio que sempre resolve o
+// usuário autenticado, para isolar os testes de handler da lógica de auth.
+func newTestRouter(repo UserRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	authRepo := &mockUserRepository{
+		GetUserFunc: func(ctx context.Context, userID int) (*User, error) {
+			return &User{ID: userID, Name: "Test User", Email: "test@example.com"}, nil
+		},
+	}
+	authService := NewAuthService(authRepo, NewInMemoryTokenStore(), AuthConfig{JWTSecret: testJWTSecret, TokenTTL: time.Hour})
+
+	ConfigureUserRoutes(router, NewUserService(repo, nil), authService)
+	return router
+}
+
+// authHeader assina um JWT válido com testJWTSecret, para os testes de
+// handler que não exercitam RequireAuth em si.
+func authHeader(t *testing.T) string {
+	t.Helper()
+	now := time.Now()
+	claims := authClaims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("sign test token: %v", err)
+	}
+	return "Bearer " + signed
+}
+
+func TestGetUserHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		repo       *mockUserRepository
+		wantStatus int
+	}{
+		{
+			name: "found",
+			path: "/users/1",
+			repo: &mockUserRepository{
+				GetUserFunc: func(ctx context.Context, userID int) (*User, error) {
+					return &User{ID: userID, Name: "John Doe", Email: "john@example.com"}, nil
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			path: "/users/404",
+			repo: &mockUserRepository{
+				GetUserFunc: func(ctx context.Context, userID int) (*User, error) {
+					return nil, sql.ErrNoRows
+				},
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid id",
+			path:       "/users/abc",
+			repo:       &mockUserRepository{},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(tt.repo)
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", authHeader(t))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCreateUserHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		repo       *mockUserRepository
+		wantStatus int
+		wantLoc    string
+	}{
+		{
+			name: "created",
+			body: `{"name":"John Doe","email":"john@example.com"}`,
+			repo: &mockUserRepository{
+				SaveUserFunc: func(ctx context.Context, user *User) error {
+					user.ID = 1
+					return nil
+				},
+			},
+			wantStatus: http.StatusCreated,
+			wantLoc:    "/users/1",
+		},
+		{
+			name:       "invalid body",
+			body:       `{"name":"John Doe"}`,
+			repo:       &mockUserRepository{},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(tt.repo)
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", authHeader(t))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantLoc != "" {
+				if got := rec.Header().Get("Location"); got != tt.wantLoc {
+					t.Fatalf("Location = %q, want %q", got, tt.wantLoc)
+				}
+				var resp userResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("unmarshal response: %v", err)
+				}
+				if resp.ID != 1 {
+					t.Fatalf("ID = %d, want 1", resp.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateUserHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		repo       *mockUserRepository
+		wantStatus int
+	}{
+		{
+			name: "updated",
+			path: "/users/1",
+			body: `{"name":"John Smith","email":"john.smith@example.com"}`,
+			repo: &mockUserRepository{
+				GetUserFunc: func(ctx context.Context, userID int) (*User, error) {
+					return &User{ID: userID, Name: "John Doe", Email: "john@example.com"}, nil
+				},
+				UpdateUserFunc: func(ctx context.Context, user *User) error {
+					return nil
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			path: "/users/404",
+			body: `{"name":"John Smith","email":"john.smith@example.com"}`,
+			repo: &mockUserRepository{
+				GetUserFunc: func(ctx context.Context, userID int) (*User, error) {
+					return nil, sql.ErrNoRows
+				},
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid id",
+			path:       "/users/abc",
+			body:       `{"name":"John Smith","email":"john.smith@example.com"}`,
+			repo:       &mockUserRepository{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid body",
+			path:       "/users/1",
+			body:       `{"name":"John Smith"}`,
+			repo:       &mockUserRepository{},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(tt.repo)
+			req := httptest.NewRequest(http.MethodPut, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", authHeader(t))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDeleteUserHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		repo       *mockUserRepository
+		wantStatus int
+	}{
+		{
+			name: "deleted",
+			path: "/users/1",
+			repo: &mockUserRepository{
+				DeleteUserFunc: func(ctx context.Context, userID int) error {
+					return nil
+				},
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "invalid id",
+			path:       "/users/abc",
+			repo:       &mockUserRepository{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "repository error",
+			path: "/users/1",
+			repo: &mockUserRepository{
+				DeleteUserFunc: func(ctx context.Context, userID int) error {
+					return errors.New("boom")
+				},
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(tt.repo)
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			req.Header.Set("Authorization", authHeader(t))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestListUsersHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		repo       *mockUserRepository
+		wantStatus int
+		wantTotal  int64
+		wantItems  int
+	}{
+		{
+			name:  "paginated",
+			query: "?page=2&page_size=1",
+			repo: &mockUserRepository{
+				ListUsersFunc: func(ctx context.Context, query ListUsersQuery) (ListUsersResult, error) {
+					if query.Page != 2 || query.PageSize != 1 {
+						t.Fatalf("query = %+v, want Page=2 PageSize=1", query)
+					}
+					return ListUsersResult{Items: []*User{{ID: 2, Name: "Jane Doe", Email: "jane@example.com"}}, Total: 2}, nil
+				},
+			},
+			wantStatus: http.StatusOK,
+			wantTotal:  2,
+			wantItems:  1,
+		},
+		{
+			name:  "filtered by name",
+			query: "?name=Jane",
+			repo: &mockUserRepository{
+				ListUsersFunc: func(ctx context.Context, query ListUsersQuery) (ListUsersResult, error) {
+					if query.NameLike != "Jane" {
+						t.Fatalf("NameLike = %q, want %q", query.NameLike, "Jane")
+					}
+					return ListUsersResult{Items: []*User{{ID: 2, Name: "Jane Doe", Email: "jane@example.com"}}, Total: 1}, nil
+				},
+			},
+			wantStatus: http.StatusOK,
+			wantTotal:  1,
+			wantItems:  1,
+		},
+		{
+			name: "repository error",
+			repo: &mockUserRepository{
+				ListUsersFunc: func(ctx context.Context, query ListUsersQuery) (ListUsersResult, error) {
+					return ListUsersResult{}, errors.New("boom")
+				},
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(tt.repo)
+			req := httptest.NewRequest(http.MethodGet, "/users"+tt.query, nil)
+			req.Header.Set("Authorization", authHeader(t))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp listUsersResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unmarshal response: %v", err)
+			}
+			if resp.Total != tt.wantTotal {
+				t.Fatalf("Total = %d, want %d", resp.Total, tt.wantTotal)
+			}
+			if len(resp.Items) != tt.wantItems {
+				t.Fatalf("len(Items) = %d, want %d", len(resp.Items), tt.wantItems)
+			}
+		})
+	}
+}
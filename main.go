@@ -1,31 +1,58 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 // Camada de domínio
 type User struct {
-	ID    int
-	Name  string
-	Email string
+	ID       int `gorm:"primaryKey"`
+	Name     string
+	Email    string
+	Password string `json:"-"` // hash bcrypt, nunca a senha em texto plano; nunca serializado
+}
+
+// ListUsersQuery descreve uma busca paginada e filtrada sobre os usuários.
+type ListUsersQuery struct {
+	Page      int    // 1-indexed; valores < 1 são tratados como 1
+	PageSize  int    // valores <= 0 desabilitam o LIMIT
+	NameLike  string // filtro por nome, casado com LIKE
+	EmailLike string // filtro por e-mail, casado com LIKE
+	SortBy    string // coluna de ordenação: "id", "name" ou "email" (default "id")
+}
+
+// ListUsersResult é a página de usuários devolvida por ListUsers, junto com
+// o total de registros que casam com os filtros (ignorando a paginação).
+type ListUsersResult struct {
+	Items []*User
+	Total int64
 }
 
 type UserRepository interface {
-	GetUser(userID int) (*User, error)
-	GetAllUsers() ([]*User, error)
-	SaveUser(user *User) error
-	UpdateUser(user *User) error
-	DeleteUser(userID int) error
+	GetUser(ctx context.Context, userID int) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetAllUsers(ctx context.Context) ([]*User, error)
+	ListUsers(ctx context.Context, query ListUsersQuery) (ListUsersResult, error)
+	SaveUser(ctx context.Context, user *User) error
+	UpdateUser(ctx context.Context, user *User) error
+	DeleteUser(ctx context.Context, userID int) error
 }
 
 // Camada de infraestrutura
 type SQLiteUserRepository struct {
 	db *sql.DB
+	// outbox, quando não nil, faz cada escrita gravar seu DomainEvent na
+	// tabela outbox dentro da mesma transação da mutação.
+	outbox *OutboxEventPublisher
 }
 
 func NewSQLiteUserRepository(dbFile string) (*SQLiteUserRepository, error) {
@@ -33,134 +60,326 @@ func NewSQLiteUserRepository(dbFile string) (*SQLiteUserRepository, error) {
 	if err != nil {
 		return nil, err
 	}
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS users (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT,
-			email TEXT
-		)
-	`)
-	if err != nil {
+	if err := Migrate(db); err != nil {
 		return nil, err
 	}
 	return &SQLiteUserRepository{db: db}, nil
 }
 
-func (r *SQLiteUserRepository) GetUser(userID int) (*User, error) {
-	row := r.db.QueryRow("SELECT id, name, email FROM users WHERE id = ?", userID)
+// NewSQLiteUserRepositoryWithOutbox é como NewSQLiteUserRepository, mas
+// grava UserCreated/UserUpdated/UserDeleted em outbox na mesma transação de
+// cada mutação, para entrega at-least-once via OutboxEventPublisher.Dispatch.
+func NewSQLiteUserRepositoryWithOutbox(dbFile string, outbox *OutboxEventPublisher) (*SQLiteUserRepository, error) {
+	repo, err := NewSQLiteUserRepository(dbFile)
+	if err != nil {
+		return nil, err
+	}
+	repo.outbox = outbox
+	return repo, nil
+}
+
+func (r *SQLiteUserRepository) GetUser(ctx context.Context, userID int) (*User, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT id, name, email, password FROM users WHERE id = ?", userID)
+	user := &User{}
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUserByEmail busca um usuário pelo e-mail exato, usado por AuthService
+// para o lookup de login filtrando no próprio SQL, em vez de carregar todos
+// os usuários. A coluna email não tem índice.
+func (r *SQLiteUserRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT id, name, email, password FROM users WHERE email = ?", email)
 	user := &User{}
-	err := row.Scan(&user.ID, &user.Name, &user.Email)
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (r *SQLiteUserRepository) GetAllUsers() ([]*User, error) {
-	rows, err := r.db.Query("SELECT id, name, email FROM users")
+// GetAllUsers é um atalho para ListUsers sem filtros nem paginação, mantido
+// por compatibilidade com chamadores existentes.
+func (r *SQLiteUserRepository) GetAllUsers(ctx context.Context) ([]*User, error) {
+	result, err := r.ListUsers(ctx, ListUsersQuery{Page: 1, PageSize: 0})
 	if err != nil {
 		return nil, err
 	}
+	return result.Items, nil
+}
+
+var sortableColumns = map[string]string{
+	"id":    "id",
+	"name":  "name",
+	"email": "email",
+}
+
+// ListUsers constrói uma busca paginada com filtros opcionais por nome e
+// e-mail. PageSize <= 0 desabilita o LIMIT, devolvendo todos os resultados.
+func (r *SQLiteUserRepository) ListUsers(ctx context.Context, query ListUsersQuery) (ListUsersResult, error) {
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+
+	var whereClauses []string
+	var args []interface{}
+	if query.NameLike != "" {
+		whereClauses = append(whereClauses, "name LIKE ?")
+		args = append(args, "%"+query.NameLike+"%")
+	}
+	if query.EmailLike != "" {
+		whereClauses = append(whereClauses, "email LIKE ?")
+		args = append(args, "%"+query.EmailLike+"%")
+	}
+
+	where := ""
+	if len(whereClauses) > 0 {
+		where = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var total int64
+	countRow := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where, args...)
+	if err := countRow.Scan(&total); err != nil {
+		return ListUsersResult{}, err
+	}
+
+	sortColumn, ok := sortableColumns[query.SortBy]
+	if !ok {
+		sortColumn = "id"
+	}
+
+	listQuery := "SELECT id, name, email, password FROM users" + where + " ORDER BY " + sortColumn
+	if query.PageSize > 0 {
+		listQuery += " LIMIT ? OFFSET ?"
+		args = append(args, query.PageSize, (page-1)*query.PageSize)
+	}
+
+	rows, err := r.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		return ListUsersResult{}, err
+	}
 	defer rows.Close()
 
-	users := []*User{}
+	items := []*User{}
 	for rows.Next() {
 		user := &User{}
-		err := rows.Scan(&user.ID, &user.Name, &user.Email)
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Password); err != nil {
+			return ListUsersResult{}, err
+		}
+		items = append(items, user)
+	}
+
+	return ListUsersResult{Items: items, Total: total}, nil
+}
+
+func (r *SQLiteUserRepository) SaveUser(ctx context.Context, user *User) error {
+	return r.withOutboxTx(ctx, func(tx *sql.Tx) (DomainEvent, error) {
+		result, err := tx.ExecContext(ctx, "INSERT INTO users (name, email, password) VALUES (?, ?, ?)", user.Name, user.Email, user.Password)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, user)
-	}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		user.ID = int(id)
+		return UserCreated{User: user, At: time.Now()}, nil
+	})
+}
+
+func (r *SQLiteUserRepository) UpdateUser(ctx context.Context, user *User) error {
+	return r.withOutboxTx(ctx, func(tx *sql.Tx) (DomainEvent, error) {
+		_, err := tx.ExecContext(ctx, "UPDATE users SET name = ?, email = ?, password = ? WHERE id = ?", user.Name, user.Email, user.Password, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return UserUpdated{User: user, At: time.Now()}, nil
+	})
+}
 
-	return users, nil
+func (r *SQLiteUserRepository) DeleteUser(ctx context.Context, userID int) error {
+	return r.withOutboxTx(ctx, func(tx *sql.Tx) (DomainEvent, error) {
+		_, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = ?", userID)
+		if err != nil {
+			return nil, err
+		}
+		return UserDeleted{UserID: userID, At: time.Now()}, nil
+	})
 }
 
-func (r *SQLiteUserRepository) SaveUser(user *User) error {
-	result, err := r.db.Exec("INSERT INTO users (name, email) VALUES (?, ?)", user.Name, user.Email)
+// withOutboxTx executa mutate dentro de uma transação e, se r.outbox estiver
+// configurado, grava o DomainEvent devolvido por mutate na mesma transação
+// antes de fazer commit.
+func (r *SQLiteUserRepository) withOutboxTx(ctx context.Context, mutate func(tx *sql.Tx) (DomainEvent, error)) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	user.ID, _ = result.LastInsertId()
-	return nil
-}
 
-func (r *SQLiteUserRepository) UpdateUser(user *User) error {
-	_, err := r.db.Exec("UPDATE users SET name = ?, email = ? WHERE id = ?", user.Name, user.Email, user.ID)
-	return err
-}
+	event, err := mutate(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 
-func (r *SQLiteUserRepository) DeleteUser(userID int) error {
-	_, err := r.db.Exec("DELETE FROM users WHERE id = ?", userID)
-	return err
+	if r.outbox != nil {
+		if err := r.outbox.EnqueueInTx(ctx, tx, event); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // Camada de aplicação
 type UserService struct {
 	userRepository UserRepository
+	eventPublisher EventPublisher
 }
 
-func NewUserService(userRepository UserRepository) *UserService {
+func NewUserService(userRepository UserRepository, eventPublisher EventPublisher) *UserService {
 	return &UserService{
 		userRepository: userRepository,
+		eventPublisher: eventPublisher,
+	}
+}
+
+// publish notifica eventPublisher, se houver um configurado, sem jamais
+// falhar a operação de domínio por causa de um erro de publicação.
+func (s *UserService) publish(ctx context.Context, event DomainEvent) {
+	if s.eventPublisher == nil {
+		return
 	}
+	_ = s.eventPublisher.Publish(ctx, event)
 }
 
-func (s *UserService) GetUser(userID int) (*User, error) {
-	return s.userRepository.GetUser(userID)
+func (s *UserService) GetUser(ctx context.Context, userID int) (*User, error) {
+	return s.userRepository.GetUser(ctx, userID)
 }
 
-func (s *UserService) GetAllUsers() ([]*User, error) {
-	return s.userRepository.GetAllUsers()
+func (s *UserService) GetAllUsers(ctx context.Context) ([]*User, error) {
+	return s.userRepository.GetAllUsers(ctx)
 }
 
-func (s *UserService) CreateUser(name, email string) error {
+func (s *UserService) ListUsers(ctx context.Context, query ListUsersQuery) (ListUsersResult, error) {
+	return s.userRepository.ListUsers(ctx, query)
+}
+
+func (s *UserService) CreateUser(ctx context.Context, name, email string) (*User, error) {
 	user := &User{
 		Name:  name,
 		Email: email,
 	}
-	return s.userRepository.SaveUser(user)
+	if err := s.userRepository.SaveUser(ctx, user); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, UserCreated{User: user, At: time.Now()})
+	return user, nil
 }
 
-func (s *UserService) UpdateUser(userID int, name, email string) error {
-	user, err := s.userRepository.GetUser(userID)
+func (s *UserService) UpdateUser(ctx context.Context, userID int, name, email string) error {
+	user, err := s.userRepository.GetUser(ctx, userID)
 	if err != nil {
 		return err
 	}
 	user.Name = name
 	user.Email = email
-	return s.userRepository.UpdateUser(user)
+	if err := s.userRepository.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+	s.publish(ctx, UserUpdated{User: user, At: time.Now()})
+	return nil
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, userID int) error {
+	if err := s.userRepository.DeleteUser(ctx, userID); err != nil {
+		return err
+	}
+	s.publish(ctx, UserDeleted{UserID: userID, At: time.Now()})
+	return nil
 }
 
-func (s *UserService) DeleteUser(userID int) error {
-	return s.userRepository.DeleteUser(userID)
+// runMigrateCLI implementa o subcomando `migrate`, permitindo operar o
+// schema (`migrate up`, `migrate down N`, `migrate status`) sem subir a
+// aplicação.
+func runMigrateCLI(args []string, dbFile string) error {
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate <up|down|status> [steps]")
+	}
+
+	switch args[0] {
+	case "up":
+		return Migrate(db)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q: %w", args[1], err)
+			}
+			steps = parsed
+		}
+		return Rollback(db, steps)
+	case "status":
+		statuses, err := Status(db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			fmt.Printf("%03d_%s applied=%t\n", s.Version, s.Name, s.Applied)
+		}
+		return nil
+	default:
+		return fmt.Errorf("usage: migrate <up|down|status> [steps]")
+	}
 }
 
 // Exemplo de uso
 func main() {
 	dbFile := "users.db"
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCLI(os.Args[2:], dbFile); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	ctx := context.Background()
+
 	repository, err := NewSQLiteUserRepository(dbFile)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	service := NewUserService(repository)
+	service := NewUserService(repository, NewInProcessEventPublisher())
 
 	// Criação de usuário
-	err = service.CreateUser("John Doe", "john@example.com")
+	_, err = service.CreateUser(ctx, "John Doe", "john@example.com")
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// Obtenção de usuário por ID
-	user, err := service.GetUser(1)
+	user, err := service.GetUser(ctx, 1)
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Println(user.ID, user.Name, user.Email)
 
 	// Obtenção de todos os usuários
-	users, err := service.GetAllUsers()
+	users, err := service.GetAllUsers(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -169,13 +388,13 @@ func main() {
 	}
 
 	// Atualização de usuário
-	err = service.UpdateUser(1, "John Smith", "john.smith@example.com")
+	err = service.UpdateUser(ctx, 1, "John Smith", "john.smith@example.com")
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// Deleção de usuário
-	err = service.DeleteUser(1)
+	err = service.DeleteUser(ctx, 1)
 	if err != nil {
 		log.Fatal(err)
 	}
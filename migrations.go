@@ -0,0 +1,253 @@
+package main
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Camada de infraestrutura (migrações de schema)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migration é um par de scripts SQL (up/down) versionado por um inteiro
+// sequencial, extraído de arquivos `NNN_nome.up.sql` / `NNN_nome.down.sql`.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// loadMigrations lê migrationFiles e monta a lista de migrations ordenada
+// por versão.
+func loadMigrations() ([]migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		version, name, direction, ok := parseMigrationFileName(fileName)
+		if !ok {
+			continue
+		}
+
+		content, err := migrationFiles.ReadFile("migrations/" + fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: name}
+			byVersion[version] = m
+		}
+		switch direction {
+		case "up":
+			m.up = string(content)
+		case "down":
+			m.down = string(content)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// parseMigrationFileName extrai versão, nome e direção (up/down) de um nome
+// de arquivo no formato "001_create_users.up.sql".
+func parseMigrationFileName(fileName string) (version int, name string, direction string, ok bool) {
+	trimmed := strings.TrimSuffix(fileName, ".sql")
+	parts := strings.SplitN(trimmed, ".", 2)
+	if len(parts) != 2 {
+		return 0, "", "", false
+	}
+	direction = parts[1]
+	if direction != "up" && direction != "down" {
+		return 0, "", "", false
+	}
+
+	versionAndName := strings.SplitN(parts[0], "_", 2)
+	if len(versionAndName) != 2 {
+		return 0, "", "", false
+	}
+	version, err := strconv.Atoi(versionAndName[0])
+	if err != nil {
+		return 0, "", "", false
+	}
+	return version, versionAndName[1], direction, true
+}
+
+// ensureSchemaMigrationsTable cria a tabela que controla quais versões já
+// foram aplicadas.
+func ensureSchemaMigrationsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT
+		)
+	`)
+	return err
+}
+
+func appliedVersions(db *sql.DB) (map[int]bool, error) {
+	rows, err := db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, nil
+}
+
+// Migrate aplica, em ordem, toda migration `up` ainda não registrada em
+// schema_migrations, cada uma dentro da sua própria transação.
+func Migrate(db *sql.DB) error {
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(m.up); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate: applying %03d_%s: %w", m.version, m.name, err)
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.version, m.name); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Rollback desfaz as `steps` migrations aplicadas mais recentes, na ordem
+// inversa em que foram aplicadas.
+func Rollback(db *sql.DB, steps int) error {
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	byVersion := map[int]migration{}
+	for _, m := range migrations {
+		byVersion[m.version] = m
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+	var appliedVersionList []int
+	for version := range applied {
+		appliedVersionList = append(appliedVersionList, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(appliedVersionList)))
+
+	if steps > len(appliedVersionList) {
+		steps = len(appliedVersionList)
+	}
+
+	for i := 0; i < steps; i++ {
+		version := appliedVersionList[i]
+		m, ok := byVersion[version]
+		if !ok {
+			return fmt.Errorf("migrate: no migration file found for applied version %03d", version)
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(m.down); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate: rolling back %03d_%s: %w", m.version, m.name, err)
+		}
+		if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", version); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MigrationStatus descreve se uma migration conhecida já foi aplicada.
+type MigrationStatus struct {
+	Version int
+	Name    string
+	Applied bool
+}
+
+// Status lista todas as migrations conhecidas e se já foram aplicadas,
+// usado pelo subcomando `migrate status`.
+func Status(db *sql.DB) ([]MigrationStatus, error) {
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		statuses = append(statuses, MigrationStatus{
+			Version: m.version,
+			Name:    m.name,
+			Applied: applied[m.version],
+		})
+	}
+	return statuses, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Camada de infraestrutura (persistência plugável)
+
+// RepoConfig seleciona e parametriza o adapter de persistência que
+// NewUserRepository deve construir.
+type RepoConfig struct {
+	// Driver é um dos: "sqlite-raw" (database/sql puro), "sqlite", "postgres"
+	// ou "mysql" (estes três últimos via GORM). Apenas "sqlite-raw" e
+	// "sqlite" rodam as migrations embutidas (ver NewGORMUserRepository);
+	// "postgres" e "mysql" não têm revoked_tokens/outbox.
+	Driver string
+	// DSN é a connection string/arquivo esperado pelo driver escolhido.
+	DSN string
+	// AllowUnmigratedSchema precisa ser true para usar "postgres" ou
+	// "mysql": as migrations embutidas são SQLite-específicas, então esses
+	// drivers rodam só AutoMigrate(&User{}), sem schema_migrations,
+	// revoked_tokens nem outbox. Exigir o opt-in evita que alguém escolha
+	// esses drivers sem perceber a lacuna.
+	AllowUnmigratedSchema bool
+}
+
+// NewUserRepository constrói o UserRepository correspondente a cfg.Driver,
+// permitindo trocar o backend de persistência sem tocar na camada de
+// aplicação.
+func NewUserRepository(cfg RepoConfig) (UserRepository, error) {
+	switch cfg.Driver {
+	case "sqlite-raw":
+		return NewSQLiteUserRepository(cfg.DSN)
+	case "sqlite", "postgres", "mysql":
+		return NewGORMUserRepository(cfg)
+	default:
+		return nil, fmt.Errorf("persistence: unknown driver %q", cfg.Driver)
+	}
+}
+
+// GORMUserRepository implementa UserRepository sobre gorm.io/gorm, suportando
+// sqlite, postgres e mysql por trás da mesma DSN-based configuration.
+type GORMUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGORMUserRepository abre a conexão indicada por cfg e garante que o
+// schema exista antes de devolver o repositório.
+//
+// Para "sqlite" isso roda as mesmas migrations embutidas usadas por
+// NewSQLiteUserRepository (via Migrate), deixando schema_migrations,
+// revoked_tokens e outbox disponíveis para SQLiteTokenStore e para o outbox
+// de eventos. Para "postgres" e "mysql" as migrations embutidas usam sintaxe
+// específica do SQLite (AUTOINCREMENT, tipos SQLite) e não se aplicam; esses
+// drivers caem de volta para AutoMigrate(&User{}) e exigem
+// cfg.AllowUnmigratedSchema para deixar claro, no call site, que
+// schema_migrations/revoked_tokens/outbox não existirão.
+func NewGORMUserRepository(cfg RepoConfig) (*GORMUserRepository, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case "sqlite":
+		dialector = sqlite.Open(cfg.DSN)
+	case "postgres":
+		dialector = postgres.Open(cfg.DSN)
+	case "mysql":
+		dialector = mysql.Open(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("persistence: gorm driver %q not supported", cfg.Driver)
+	}
+
+	if (cfg.Driver == "postgres" || cfg.Driver == "mysql") && !cfg.AllowUnmigratedSchema {
+		return nil, fmt.Errorf("persistence: driver %q has no migrations (only AutoMigrate); set RepoConfig.AllowUnmigratedSchema to use it anyway", cfg.Driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Driver == "sqlite" {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		if err := Migrate(sqlDB); err != nil {
+			return nil, err
+		}
+	} else if err := db.AutoMigrate(&User{}); err != nil {
+		return nil, err
+	}
+
+	return &GORMUserRepository{db: db}, nil
+}
+
+func (r *GORMUserRepository) GetUser(ctx context.Context, userID int) (*User, error) {
+	user := &User{}
+	err := r.db.WithContext(ctx).First(user, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *GORMUserRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	user := &User{}
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *GORMUserRepository) GetAllUsers(ctx context.Context) ([]*User, error) {
+	result, err := r.ListUsers(ctx, ListUsersQuery{Page: 1, PageSize: 0})
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+func (r *GORMUserRepository) ListUsers(ctx context.Context, query ListUsersQuery) (ListUsersResult, error) {
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+
+	db := r.db.WithContext(ctx).Model(&User{})
+	if query.NameLike != "" {
+		db = db.Where("name LIKE ?", "%"+query.NameLike+"%")
+	}
+	if query.EmailLike != "" {
+		db = db.Where("email LIKE ?", "%"+query.EmailLike+"%")
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return ListUsersResult{}, err
+	}
+
+	sortColumn, ok := sortableColumns[query.SortBy]
+	if !ok {
+		sortColumn = "id"
+	}
+	db = db.Order(sortColumn)
+	if query.PageSize > 0 {
+		db = db.Limit(query.PageSize).Offset((page - 1) * query.PageSize)
+	}
+
+	var items []*User
+	if err := db.Find(&items).Error; err != nil {
+		return ListUsersResult{}, err
+	}
+
+	return ListUsersResult{Items: items, Total: total}, nil
+}
+
+func (r *GORMUserRepository) SaveUser(ctx context.Context, user *User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+func (r *GORMUserRepository) UpdateUser(ctx context.Context, user *User) error {
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+func (r *GORMUserRepository) DeleteUser(ctx context.Context, userID int) error {
+	return r.db.WithContext(ctx).Delete(&User{}, userID).Error
+}